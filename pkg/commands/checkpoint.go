@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// Checkpoint is a single CRIU checkpoint taken of a container, enriched with
+// the timestamp and on-disk size of its checkpoint directory (the Docker API
+// itself only reports the name).
+type Checkpoint struct {
+	Name      string
+	Dir       string
+	CreatedAt time.Time
+	SizeBytes int64
+}
+
+// checkpointsEnabled reports whether the checkpoint subsystem should be
+// offered at all: it's gated behind userConfig.Experimental.Checkpoints
+// *and* the daemon reporting an experimental build (Info().ExperimentalBuild),
+// since the endpoints are still experimental. Whether CRIU itself is
+// available is not checked here; the daemon rejects the call if it isn't.
+func (c *DockerCommand) checkpointsEnabled() (bool, error) {
+	if !c.Config.UserConfig.Experimental.Checkpoints {
+		return false, nil
+	}
+
+	info, err := c.client().Info(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	return info.ExperimentalBuild, nil
+}
+
+// CheckpointList returns the checkpoints stored for a container, newest
+// first, stat-ing checkpointDir to fill in CreatedAt/SizeBytes since the
+// Docker API response is just a list of names.
+func (c *DockerCommand) CheckpointList(containerID string, checkpointDir string) ([]*Checkpoint, error) {
+	enabled, err := c.checkpointsEnabled()
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, fmt.Errorf(c.Tr.CheckpointsNotEnabled)
+	}
+
+	summaries, err := c.client().CheckpointList(context.Background(), containerID, types.CheckpointListOptions{
+		CheckpointDir: checkpointDir,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(c.Tr.CheckpointsNotSupported, err)
+	}
+
+	checkpoints := make([]*Checkpoint, len(summaries))
+	for i, summary := range summaries {
+		checkpoints[i] = c.statCheckpoint(summary.Name, checkpointDir)
+	}
+
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].CreatedAt.After(checkpoints[j].CreatedAt)
+	})
+
+	return checkpoints, nil
+}
+
+// statCheckpoint fills in a Checkpoint's CreatedAt/SizeBytes by walking its
+// on-disk directory; callers only ever have the name from the API itself.
+func (c *DockerCommand) statCheckpoint(name string, checkpointDir string) *Checkpoint {
+	dir := filepath.Join(checkpointDir, name)
+	checkpoint := &Checkpoint{Name: name, Dir: dir}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		c.Log.Warn(err)
+		return checkpoint
+	}
+	checkpoint.CreatedAt = info.ModTime()
+
+	_ = filepath.Walk(dir, func(_ string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return nil
+		}
+		checkpoint.SizeBytes += fi.Size()
+		return nil
+	})
+
+	return checkpoint
+}
+
+// CheckpointCreate takes a checkpoint of a running container. If leaveRunning
+// is false the container is stopped as part of the checkpoint, matching
+// `docker checkpoint create` without --leave-running.
+func (c *DockerCommand) CheckpointCreate(containerID string, name string, checkpointDir string, leaveRunning bool) error {
+	enabled, err := c.checkpointsEnabled()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return fmt.Errorf(c.Tr.CheckpointsNotEnabled)
+	}
+
+	err = c.client().CheckpointCreate(context.Background(), containerID, types.CheckpointCreateOptions{
+		CheckpointID:  name,
+		CheckpointDir: checkpointDir,
+		Exit:          !leaveRunning,
+	})
+	if err != nil {
+		return fmt.Errorf(c.Tr.CheckpointsNotSupported, err)
+	}
+
+	return nil
+}
+
+// CheckpointDelete removes a checkpoint by name.
+func (c *DockerCommand) CheckpointDelete(containerID string, name string, checkpointDir string) error {
+	enabled, err := c.checkpointsEnabled()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return fmt.Errorf(c.Tr.CheckpointsNotEnabled)
+	}
+
+	err = c.client().CheckpointDelete(context.Background(), containerID, types.CheckpointDeleteOptions{
+		CheckpointID:  name,
+		CheckpointDir: checkpointDir,
+	})
+	if err != nil {
+		return fmt.Errorf(c.Tr.CheckpointsNotSupported, err)
+	}
+
+	return nil
+}
+
+// StartFromCheckpoint starts a (stopped) container by restoring it from one
+// of its own checkpoints, via the checkpoint/checkpoint-dir query params on
+// the usual container start call.
+func (c *DockerCommand) StartFromCheckpoint(containerID string, checkpointName string, checkpointDir string) error {
+	enabled, err := c.checkpointsEnabled()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return fmt.Errorf(c.Tr.CheckpointsNotEnabled)
+	}
+
+	err = c.client().ContainerStart(context.Background(), containerID, types.ContainerStartOptions{
+		CheckpointID:  checkpointName,
+		CheckpointDir: checkpointDir,
+	})
+	if err != nil {
+		return fmt.Errorf(c.Tr.CheckpointsNotSupported, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+package commands
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/client"
+	"github.com/jesseduffield/lazydocker/pkg/commands/ssh"
+	"github.com/jesseduffield/lazydocker/pkg/config"
+	"github.com/jesseduffield/lazydocker/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// DockerContext is a single Docker endpoint the user can switch to: the local
+// daemon, a remote TLS-protected host, or an SSH-tunnelled one.
+type DockerContext struct {
+	Name        string
+	Host        string
+	TLSCertPath string
+	TLSVerify   bool
+	SSHHost     string
+}
+
+// defaultContextName is the context lazydocker starts on: whatever
+// client.FromEnv/the SSH tunnel in NewDockerCommand resolves to.
+const defaultContextName = "default"
+
+// ContextManager discovers the DockerContexts available to the user, both the
+// ones `docker context create` has written to ~/.docker/contexts/meta and the
+// ones configured by hand under userConfig.DockerContexts, and builds a
+// *client.Client for whichever one is selected.
+type ContextManager struct {
+	Log    *logrus.Entry
+	Config *config.AppConfig
+}
+
+func NewContextManager(log *logrus.Entry, config *config.AppConfig) *ContextManager {
+	return &ContextManager{Log: log, Config: config}
+}
+
+// List returns every known context, with "default" always first.
+func (m *ContextManager) List() []DockerContext {
+	contexts := []DockerContext{{Name: defaultContextName}}
+
+	fromDockerCLI, err := m.loadFromDockerConfig()
+	if err != nil {
+		m.Log.Warn(err)
+	} else {
+		contexts = append(contexts, fromDockerCLI...)
+	}
+
+	for _, uc := range m.Config.UserConfig.DockerContexts {
+		contexts = append(contexts, DockerContext{
+			Name:        uc.Name,
+			Host:        uc.Host,
+			TLSCertPath: uc.TLSCertPath,
+			TLSVerify:   !uc.TLSInsecureSkipVerify,
+			SSHHost:     uc.SSHHost,
+		})
+	}
+
+	return contexts
+}
+
+// Find returns the named context, or an error if it isn't known.
+func (m *ContextManager) Find(name string) (DockerContext, error) {
+	for _, ctx := range m.List() {
+		if ctx.Name == name {
+			return ctx, nil
+		}
+	}
+
+	return DockerContext{}, fmt.Errorf("unknown docker context: %s", name)
+}
+
+// dockerContextMeta mirrors the subset of ~/.docker/contexts/meta/<hash>/meta.json
+// that `docker context` writes that we actually care about.
+type dockerContextMeta struct {
+	Name      string `json:"Name"`
+	Endpoints struct {
+		Docker struct {
+			Host          string `json:"Host"`
+			SkipTLSVerify bool   `json:"SkipTLSVerify"`
+		} `json:"docker"`
+	} `json:"Endpoints"`
+}
+
+func (m *ContextManager) loadFromDockerConfig() ([]DockerContext, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	metaDir := filepath.Join(home, ".docker", "contexts", "meta")
+	entries, err := os.ReadDir(metaDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	contexts := []DockerContext{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(metaDir, entry.Name(), "meta.json"))
+		if err != nil {
+			m.Log.Warn(err)
+			continue
+		}
+
+		var meta dockerContextMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			m.Log.Warn(err)
+			continue
+		}
+
+		contexts = append(contexts, DockerContext{
+			Name:        meta.Name,
+			Host:        meta.Endpoints.Docker.Host,
+			TLSVerify:   !meta.Endpoints.Docker.SkipTLSVerify,
+			TLSCertPath: filepath.Join(home, ".docker", "contexts", "tls", entry.Name(), "docker"),
+		})
+	}
+
+	return contexts, nil
+}
+
+// buildClient resolves ctx into a ready-to-use *client.Client, along with any
+// io.Closer (an SSH tunnel) that must be torn down when we move away from it.
+func (m *ContextManager) buildClient(ctx DockerContext, osCommand *OSCommand) (*client.Client, []io.Closer, error) {
+	if ctx.Name == defaultContextName && ctx.Host == "" && ctx.SSHHost == "" {
+		tunnelCloser, err := ssh.NewSSHHandler(osCommand).HandleSSHDockerHost()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cli, err := client.NewClientWithOpts(client.FromEnv, client.WithVersion(APIVersion))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return cli, []io.Closer{tunnelCloser}, nil
+	}
+
+	var closers []io.Closer
+	opts := []client.Opt{client.WithVersion(APIVersion)}
+
+	host := ctx.Host
+	if ctx.SSHHost != "" {
+		tunnelCloser, tunnelHost, err := ssh.NewSSHHandler(osCommand).HandleSSHDockerHostFor(ctx.SSHHost)
+		if err != nil {
+			return nil, nil, err
+		}
+		closers = append(closers, tunnelCloser)
+		host = tunnelHost
+	}
+
+	if host != "" {
+		opts = append(opts, client.WithHost(host))
+	}
+
+	if ctx.TLSCertPath != "" {
+		httpClient, err := tlsHTTPClient(ctx.TLSCertPath, ctx.TLSVerify)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, client.WithHTTPClient(httpClient))
+	}
+
+	cli, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cli, closers, nil
+}
+
+// tlsHTTPClient builds an http.Client configured for mTLS against a host whose
+// cert/key/ca live under certPath, following the same ca.pem/cert.pem/key.pem
+// layout the docker CLI itself expects.
+func tlsHTTPClient(certPath string, verify bool) (*http.Client, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(certPath, "cert.pem"), filepath.Join(certPath, "key.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates:       []tls.Certificate{cert},
+		InsecureSkipVerify: !verify, // nolint:gosec // explicit opt-out via DockerContexts.TLSInsecureSkipVerify
+	}
+
+	if verify {
+		caData, err := os.ReadFile(filepath.Join(certPath, "ca.pem"))
+		if err != nil {
+			return nil, err
+		}
+
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("could not parse CA certificate at %s", certPath)
+		}
+		tlsConfig.RootCAs = caPool
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}, nil
+}
+
+// SwitchContext tears down the current client (and any SSH tunnel it owns),
+// rebuilds it against the named context, re-runs the compose-config probe,
+// and atomically swaps in the new containers/services. On failure the
+// previous, still-working client is left in place.
+func (c *DockerCommand) SwitchContext(name string) error {
+	target, err := c.ContextManager.Find(name)
+	if err != nil {
+		return err
+	}
+
+	newClient, newClosers, err := c.ContextManager.buildClient(target, c.OSCommand)
+	if err != nil {
+		return err
+	}
+
+	oldClient := c.Client
+	oldClosers := c.Closers
+
+	c.clientMutex.Lock()
+	c.Client = newClient
+	c.clientMutex.Unlock()
+	c.Closers = newClosers
+
+	command := utils.ApplyTemplate(
+		c.Config.UserConfig.CommandTemplates.CheckDockerComposeConfig,
+		c.NewCommandObject(CommandObject{}),
+	)
+	c.InDockerComposeProject = c.OSCommand.RunCommand(command) == nil
+
+	if _, _, err := c.RefreshContainersAndServices(nil); err != nil {
+		// roll back to the previous, still-working endpoint
+		c.clientMutex.Lock()
+		c.Client = oldClient
+		c.clientMutex.Unlock()
+		c.Closers = oldClosers
+
+		_ = utils.CloseMany(newClosers)
+		_ = newClient.Close()
+
+		return err
+	}
+
+	_ = utils.CloseMany(oldClosers)
+	_ = oldClient.Close()
+	c.CurrentContext = target.Name
+	c.notifyRefresh()
+
+	return nil
+}
@@ -14,6 +14,8 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/imdario/mergo"
 	"github.com/jesseduffield/lazydocker/pkg/commands/ssh"
@@ -44,7 +46,41 @@ type DockerCommand struct {
 	Containers []*Container
 	// DisplayContainers is the array of containers we will display in the containers panel. If Gui.ShowAllContainers is false, this will only be those containers which aren't based on a service. This reduces clutter and duplication in the UI
 	DisplayContainers []*Container
-	Closers           []io.Closer
+	// Services is the last set of docker-compose services we resolved. We hang
+	// onto it so that MonitorEvents can re-link containers to services without
+	// shelling out to `docker-compose config` again.
+	Services []*Service
+	Closers  []io.Closer
+
+	// RefreshChan is sent on whenever MonitorEvents has changed something the GUI
+	// should redraw for. It's buffered so a burst of events doesn't block the
+	// event loop while the GUI is busy rendering.
+	RefreshChan chan struct{}
+
+	// ImagesStale/VolumesStale are set when an image/volume event comes in, so
+	// that the images/volumes panels know to refetch rather than reuse what
+	// they last rendered.
+	ImagesStale  bool
+	VolumesStale bool
+
+	// ContextManager resolves the set of Docker endpoints the user can switch
+	// between, and CurrentContext is the name of whichever one is active.
+	ContextManager *ContextManager
+	CurrentContext string
+
+	// SwarmServices/SwarmTasks are populated instead of (compose) Services
+	// when the daemon reports an active swarm; see RefreshSwarmServicesAndTasks.
+	SwarmServices []*SwarmService
+	SwarmTasks    []*SwarmTask
+
+	statCancelMutex sync.Mutex
+	statCancels     map[string]context.CancelFunc
+
+	// clientMutex guards reads/writes of Client itself (as opposed to the
+	// state the client returns), so SwitchContext can swap it out while
+	// MonitorEvents and the per-container stat monitors are reading it from
+	// their own goroutines.
+	clientMutex sync.RWMutex
 }
 
 var _ io.Closer = &DockerCommand{}
@@ -61,6 +97,10 @@ type CommandObject struct {
 	Container     *Container
 	Image         *Image
 	Volume        *Volume
+	// PullBeforeRun, when set on a run-type custom command template, tells the
+	// caller to PullImage the command's Image before invoking the command
+	// itself, so e.g. a "Recreate" command always runs against the latest tag.
+	PullBeforeRun bool
 }
 
 // NewCommandObject takes a command object and returns a default command object with the passed command object merged in
@@ -92,8 +132,13 @@ func NewDockerCommand(log *logrus.Entry, osCommand *OSCommand, tr *i18n.Translat
 		ShowExited:             true,
 		InDockerComposeProject: true,
 		Closers:                []io.Closer{tunnelCloser},
+		RefreshChan:            make(chan struct{}, 1),
+		statCancels:            map[string]context.CancelFunc{},
+		CurrentContext:         defaultContextName,
 	}
 
+	dockerCommand.ContextManager = NewContextManager(log, config)
+
 	command := utils.ApplyTemplate(
 		config.UserConfig.CommandTemplates.CheckDockerComposeConfig,
 		dockerCommand.NewCommandObject(CommandObject{}),
@@ -119,28 +164,60 @@ func (c *DockerCommand) Close() error {
 	return utils.CloseMany(c.Closers)
 }
 
+// client returns the current *client.Client under clientMutex's read lock, so
+// callers get a consistent pointer even if SwitchContext swaps it out from
+// under them mid-call. Every method that talks to the docker daemon should
+// read through this rather than the Client field directly.
+func (c *DockerCommand) client() *client.Client {
+	c.clientMutex.RLock()
+	defer c.clientMutex.RUnlock()
+	return c.Client
+}
+
+// MonitorContainerStats does a one-off pass over the currently known
+// containers and makes sure each running one has a stat monitor goroutine.
+// It exists to bootstrap monitoring at startup; once MonitorEvents is
+// running, stat streams are started and stopped in response to the
+// container's start/die events instead.
 func (c *DockerCommand) MonitorContainerStats(ctx context.Context) {
-	// periodically loop through running containers and see if we need to create a monitor goroutine for any
-	// every second we check if we need to spawn a new goroutine
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			for _, container := range c.Containers {
-				if !container.MonitoringStats {
-					go c.createClientStatMonitor(container)
-				}
-			}
+	for _, container := range c.Containers {
+		if container.Container.State == "running" && !container.MonitoringStats {
+			c.startStatMonitor(ctx, container)
 		}
 	}
 }
 
-func (c *DockerCommand) createClientStatMonitor(container *Container) {
+func (c *DockerCommand) startStatMonitor(ctx context.Context, container *Container) {
+	if container.MonitoringStats {
+		return
+	}
+
+	statCtx, cancel := context.WithCancel(ctx)
+
+	c.statCancelMutex.Lock()
+	if existing, ok := c.statCancels[container.ID]; ok {
+		existing()
+	}
+	c.statCancels[container.ID] = cancel
+	c.statCancelMutex.Unlock()
+
+	go c.createClientStatMonitor(statCtx, container)
+}
+
+func (c *DockerCommand) stopStatMonitor(containerID string) {
+	c.statCancelMutex.Lock()
+	defer c.statCancelMutex.Unlock()
+
+	if cancel, ok := c.statCancels[containerID]; ok {
+		cancel()
+		delete(c.statCancels, containerID)
+	}
+}
+
+func (c *DockerCommand) createClientStatMonitor(ctx context.Context, container *Container) {
 	container.MonitoringStats = true
-	stream, err := c.Client.ContainerStats(context.Background(), container.ID, true)
+
+	stream, err := c.client().ContainerStats(ctx, container.ID, true)
 	if err != nil {
 		// not creating error panel because if we've disconnected from docker we'll
 		// have already created an error panel
@@ -194,17 +271,251 @@ func (c *DockerCommand) RefreshContainersAndServices(currentServices []*Service)
 
 	c.assignContainersToServices(containers, services)
 
-	displayContainers := containers
+	c.Containers = containers
+	c.Services = services
+	c.relinkSwarmTasks()
+	c.refreshDisplayContainers()
+
+	return c.DisplayContainers, services, nil
+}
+
+// refreshDisplayContainers rebuilds c.DisplayContainers from c.Containers and
+// c.Services by re-applying the standalone/exited/ignored filters and the
+// configured sort. It's cheap enough to call after every surgical update that
+// MonitorEvents makes, so the containers panel stays current without a full
+// ContainerList.
+func (c *DockerCommand) refreshDisplayContainers() {
+	displayContainers := c.Containers
 	if !c.Config.UserConfig.Gui.ShowAllContainers {
-		displayContainers = c.obtainStandaloneContainers(containers, services)
+		displayContainers = c.obtainStandaloneContainers(c.Containers, c.Services)
 	}
 
-	c.Containers = containers
 	c.DisplayContainers = c.filterOutExited(displayContainers)
 	c.DisplayContainers = c.filterOutIgnoredContainers(c.DisplayContainers)
 	c.DisplayContainers = c.sortedContainers(c.DisplayContainers)
+}
 
-	return c.DisplayContainers, services, nil
+// eventsReconcileInterval is how often MonitorEvents falls back to a full
+// RefreshContainersAndServices, in case the events stream dropped something
+// (or the daemon disconnected and reconnected) between subscriptions.
+const eventsReconcileInterval = 30 * time.Second
+
+// MonitorEvents subscribes to the Docker events stream and keeps
+// c.Containers/c.DisplayContainers current by surgically applying
+// create/start/die/destroy/rename/health_status events instead of re-running
+// ContainerList/ContainerInspect on a ticker. Image and volume events mark
+// those panels' caches stale so they repaint on next read. A periodic
+// reconciliation keeps running alongside the stream as a fallback in case
+// events are dropped. RefreshChan is sent on after every change so the GUI
+// layer can select on it to trigger a redraw.
+func (c *DockerCommand) MonitorEvents(ctx context.Context) {
+	ticker := time.NewTicker(eventsReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.consumeEvents(ctx, ticker.C); err != nil {
+			c.Log.Error(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+			// give the daemon a moment before we try to resubscribe
+		}
+	}
+}
+
+// consumeEvents subscribes once and processes events/ticks until the stream
+// errors out or ctx is cancelled.
+func (c *DockerCommand) consumeEvents(ctx context.Context, reconcile <-chan time.Time) error {
+	eventsChan, errChan := c.client().Events(ctx, types.EventsOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.ContainerEventType)),
+			filters.Arg("type", string(events.ImageEventType)),
+			filters.Arg("type", string(events.VolumeEventType)),
+			filters.Arg("type", string(events.NetworkEventType)),
+		),
+	})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-reconcile:
+			if _, _, err := c.RefreshContainersAndServices(c.Services); err != nil {
+				c.Log.Error(err)
+				continue
+			}
+			c.notifyRefresh()
+		case err := <-errChan:
+			return err
+		case event := <-eventsChan:
+			c.handleDockerEvent(event)
+		}
+	}
+}
+
+// notifyRefresh pings RefreshChan without blocking if a redraw is already pending.
+func (c *DockerCommand) notifyRefresh() {
+	select {
+	case c.RefreshChan <- struct{}{}:
+	default:
+	}
+}
+
+func (c *DockerCommand) handleDockerEvent(event events.Message) {
+	switch event.Type {
+	case events.ContainerEventType:
+		c.handleContainerEvent(event)
+	case events.ImageEventType:
+		c.ImagesStale = true
+		c.notifyRefresh()
+	case events.VolumeEventType:
+		c.VolumesStale = true
+		c.notifyRefresh()
+	case events.NetworkEventType:
+		c.notifyRefresh()
+	}
+}
+
+func (c *DockerCommand) handleContainerEvent(event events.Message) {
+	switch {
+	case event.Action == "create" || event.Action == "start" || event.Action == "rename":
+		c.onContainerUpserted(event.Actor.ID, event.Action == "start")
+	case event.Action == "die" || event.Action == "destroy":
+		c.onContainerGone(event.Actor.ID, event.Action == "destroy")
+	case strings.HasPrefix(string(event.Action), "health_status"):
+		c.onContainerHealth(event.Actor.ID)
+	default:
+		return
+	}
+
+	c.notifyRefresh()
+}
+
+// onContainerUpserted inspects a single container and patches it into
+// c.Containers, re-linking it to its service, without doing a full
+// ContainerList/GetContainers pass.
+func (c *DockerCommand) onContainerUpserted(containerID string, justStarted bool) {
+	details, err := c.client().ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		// the container may already be gone by the time we get around to
+		// inspecting it; nothing to do
+		return
+	}
+
+	// ServiceMutex is held for the whole mutate-relink-refresh section below,
+	// the same scope RefreshContainersAndServices uses, so a concurrent full
+	// refresh can't interleave with this surgical update and see
+	// c.Containers/c.Services or c.DisplayContainers half-updated.
+	c.ServiceMutex.Lock()
+	defer c.ServiceMutex.Unlock()
+
+	c.ContainerMutex.Lock()
+	container := c.findOrCreateContainer(containerID)
+	container.Details = details
+	container.Container = types.Container{
+		ID:     details.ID,
+		Names:  []string{details.Name},
+		Labels: details.Config.Labels,
+		State:  details.State.Status,
+	}
+	if name, ok := details.Config.Labels["name"]; ok {
+		container.Name = name
+	} else {
+		container.Name = strings.TrimLeft(details.Name, "/")
+	}
+	container.ServiceName = details.Config.Labels["com.docker.compose.service"]
+	container.ProjectName = details.Config.Labels["com.docker.compose.project"]
+	container.ContainerNumber = details.Config.Labels["com.docker.compose.container"]
+	container.OneOff = details.Config.Labels["com.docker.compose.oneoff"] == "True"
+
+	found := false
+	for i, existing := range c.Containers {
+		if existing.ID == container.ID {
+			c.Containers[i] = container
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.Containers = append(c.Containers, container)
+	}
+	c.ContainerMutex.Unlock()
+
+	c.assignContainersToServices(c.Containers, c.Services)
+	c.relinkSwarmTasks()
+	c.refreshDisplayContainers()
+
+	if justStarted && details.State.Running {
+		c.startStatMonitor(context.Background(), container)
+	}
+}
+
+// findOrCreateContainer returns the existing *Container for id, or a freshly
+// initialised one if we haven't seen it before. Callers must hold ContainerMutex.
+func (c *DockerCommand) findOrCreateContainer(id string) *Container {
+	for _, container := range c.Containers {
+		if container.ID == id {
+			return container
+		}
+	}
+
+	return &Container{
+		ID:            id,
+		Client:        c.client(),
+		OSCommand:     c.OSCommand,
+		Log:           c.Log,
+		Config:        c.Config,
+		DockerCommand: c,
+		Tr:            c.Tr,
+	}
+}
+
+// onContainerGone reacts to a die/destroy event. On destroy the container is
+// dropped from c.Containers entirely; on die it's kept around (so its exited
+// state still shows up) but its stat monitor is torn down.
+func (c *DockerCommand) onContainerGone(containerID string, destroyed bool) {
+	c.stopStatMonitor(containerID)
+
+	if !destroyed {
+		c.onContainerUpserted(containerID, false)
+		return
+	}
+
+	// Same lock scope as onContainerUpserted: hold ServiceMutex across the
+	// mutation, relink and refresh so nothing observes them half-applied.
+	c.ServiceMutex.Lock()
+	defer c.ServiceMutex.Unlock()
+
+	c.ContainerMutex.Lock()
+	c.Containers = lo.Filter(c.Containers, func(container *Container, _ int) bool {
+		return container.ID != containerID
+	})
+	c.ContainerMutex.Unlock()
+
+	c.assignContainersToServices(c.Containers, c.Services)
+	c.relinkSwarmTasks()
+	c.refreshDisplayContainers()
+}
+
+// onContainerHealth refreshes the cached inspect details for a container
+// whose healthcheck status just changed.
+func (c *DockerCommand) onContainerHealth(containerID string) {
+	details, err := c.client().ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return
+	}
+
+	c.ContainerMutex.Lock()
+	defer c.ContainerMutex.Unlock()
+	for _, container := range c.Containers {
+		if container.ID == containerID {
+			container.Details = details
+			return
+		}
+	}
 }
 
 func (c *DockerCommand) assignContainersToServices(containers []*Container, services []*Service) {
@@ -286,7 +597,7 @@ func (c *DockerCommand) GetContainers() ([]*Container, error) {
 
 	existingContainers := c.Containers
 
-	containers, err := c.Client.ContainerList(context.Background(), types.ContainerListOptions{All: true})
+	containers, err := c.client().ContainerList(context.Background(), types.ContainerListOptions{All: true})
 	if err != nil {
 		return nil, err
 	}
@@ -374,7 +685,7 @@ func (c *DockerCommand) UpdateContainerDetails() error {
 	defer c.ContainerMutex.Unlock()
 
 	for _, container := range c.Containers {
-		details, err := c.Client.ContainerInspect(context.Background(), container.ID)
+		details, err := c.client().ContainerInspect(context.Background(), container.ID)
 		if err != nil {
 			c.Log.Error(err)
 		} else {
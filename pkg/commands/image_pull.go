@@ -0,0 +1,239 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/jesseduffield/lazydocker/pkg/utils"
+)
+
+// PullOptions configures DockerCommand.PullImage.
+type PullOptions struct {
+	// OnProgress is called once per line of the image pull's JSON progress
+	// stream, already parsed, so the GUI can render a live per-layer bar.
+	OnProgress func(PullProgress)
+}
+
+// PullProgress is a single line of `docker image pull`'s JSON progress
+// stream, trimmed down to what the progress bar needs. ImagePull returns a
+// 200 stream even when the pull ultimately fails (bad auth, unknown
+// manifest, rate-limiting, ...), so Error/ErrorDetail carry that failure
+// instead of it showing up as a non-2xx response or a scanner error.
+type PullProgress struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	ProgressDetail struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+	Error       string `json:"error"`
+	ErrorDetail struct {
+		Message string `json:"message"`
+	} `json:"errorDetail"`
+}
+
+// dockerConfigFile mirrors the subset of ~/.docker/config.json we need to
+// resolve registry auth: either a plain base64 `auth`, or a credsStore/
+// credHelpers entry naming a docker-credential-<helper> binary.
+type dockerConfigFile struct {
+	Auths map[string]struct {
+		Auth string `json:"auth"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// PullImage pulls ref, resolving registry credentials from
+// ~/.docker/config.json the same way the docker CLI does, and streams the
+// pull's progress line-by-line through opts.OnProgress.
+func (c *DockerCommand) PullImage(ref string, opts PullOptions) error {
+	registry := registryFromRef(ref)
+
+	authHeader, err := c.resolveRegistryAuth(registry)
+	if err != nil {
+		c.Log.Warn(err)
+	}
+
+	stream, err := c.client().ImagePull(context.Background(), ref, types.ImagePullOptions{
+		RegistryAuth: authHeader,
+	})
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		var progress PullProgress
+		if err := json.Unmarshal(scanner.Bytes(), &progress); err != nil {
+			continue
+		}
+
+		// ImagePull's response is a 200 stream even when the pull itself
+		// failed partway through, so this is the only place that failure
+		// surfaces.
+		if progress.Error != "" {
+			return fmt.Errorf("%s", progress.Error)
+		}
+		if progress.ErrorDetail.Message != "" {
+			return fmt.Errorf("%s", progress.ErrorDetail.Message)
+		}
+
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// registryFromRef extracts the registry host out of an image reference,
+// falling back to Docker Hub (matching the docker CLI's own default) when the
+// reference has no explicit host.
+func registryFromRef(ref string) string {
+	name := ref
+	if at := strings.IndexRune(name, '@'); at != -1 {
+		name = name[:at]
+	}
+	if idx := strings.IndexRune(name, '/'); idx != -1 {
+		candidate := name[:idx]
+		if strings.ContainsAny(candidate, ".:") || candidate == "localhost" {
+			return candidate
+		}
+	}
+	return "https://index.docker.io/v1/"
+}
+
+// resolveRegistryAuth loads ~/.docker/config.json and returns the
+// base64-encoded, X-Registry-Auth-ready types.AuthConfig for registry,
+// resolving it via a plain `auth` entry or by execing the configured
+// credsStore/credHelpers binary per the credential-helper protocol.
+func (c *DockerCommand) resolveRegistryAuth(registry string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".docker", "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	var config dockerConfigFile
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", err
+	}
+
+	if entry, ok := config.Auths[registry]; ok && entry.Auth != "" {
+		return encodeAuthConfig(decodeBasicAuth(entry.Auth))
+	}
+
+	helper := config.CredHelpers[registry]
+	if helper == "" {
+		helper = config.CredsStore
+	}
+	if helper == "" {
+		return "", nil
+	}
+
+	authConfig, err := c.execCredentialHelper(helper, registry)
+	if err != nil {
+		return "", err
+	}
+
+	return encodeAuthConfig(authConfig)
+}
+
+// decodeBasicAuth splits a base64("user:pass") `auth` entry into its parts.
+func decodeBasicAuth(auth string) types.AuthConfig {
+	decoded, err := base64.StdEncoding.DecodeString(auth)
+	if err != nil {
+		return types.AuthConfig{}
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return types.AuthConfig{}
+	}
+
+	return types.AuthConfig{Username: parts[0], Password: parts[1]}
+}
+
+// credentialHelperResponse is what `docker-credential-<helper> get` writes to
+// stdout, per the credential-helper protocol.
+type credentialHelperResponse struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// execCredentialHelper resolves registry's credentials by execing
+// docker-credential-<helper> with "get" and registry on stdin, per
+// https://github.com/docker/docker-credential-helpers.
+func (c *DockerCommand) execCredentialHelper(helper string, registry string) (types.AuthConfig, error) {
+	cmd := exec.Command(fmt.Sprintf("docker-credential-%s", helper), "get")
+	cmd.Stdin = strings.NewReader(registry)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return types.AuthConfig{}, fmt.Errorf("docker-credential-%s: %w", helper, err)
+	}
+
+	var resp credentialHelperResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return types.AuthConfig{}, err
+	}
+
+	return types.AuthConfig{
+		Username:      resp.Username,
+		Password:      resp.Secret,
+		ServerAddress: resp.ServerURL,
+	}, nil
+}
+
+// encodeAuthConfig base64-encodes an AuthConfig for the X-Registry-Auth
+// header, exactly as client.ImagePull/ImagePush expect.
+func encodeAuthConfig(authConfig types.AuthConfig) (string, error) {
+	if authConfig == (types.AuthConfig{}) {
+		return "", nil
+	}
+
+	data, err := json.Marshal(authConfig)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// RunCustomCommand applies commandTemplate against obj and runs it, first
+// pulling obj.Image if the template set obj.PullBeforeRun - e.g. a "Recreate"
+// custom command that should always run against the latest tag rather than
+// whatever's already pulled.
+func (c *DockerCommand) RunCustomCommand(obj CommandObject, commandTemplate string) error {
+	if obj.PullBeforeRun {
+		if obj.Image == nil {
+			return fmt.Errorf("PullBeforeRun requires an Image on the command object")
+		}
+
+		if err := c.PullImage(obj.Image.Name, PullOptions{}); err != nil {
+			return err
+		}
+	}
+
+	return c.OSCommand.RunCommand(utils.ApplyTemplate(commandTemplate, c.NewCommandObject(obj)))
+}
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+// SwarmService wraps a swarm.Service with the bits the services panel wants
+// to render (desired vs running replica counts, update status) and the
+// operations it can invoke on it.
+type SwarmService struct {
+	Service       swarm.Service
+	StackName     string
+	RunningTasks  int
+	DesiredTasks  int
+	DockerCommand *DockerCommand
+}
+
+// Name is the service's name, as shown in `docker service ls`.
+func (s *SwarmService) Name() string {
+	return s.Service.Spec.Name
+}
+
+// ContainerSpec exposes the image/env/mounts/networks the service renders
+// its tasks with, the same way a compose Service exposes its config.
+func (s *SwarmService) ContainerSpec() *swarm.ContainerSpec {
+	if s.Service.Spec.TaskTemplate.ContainerSpec == nil {
+		return &swarm.ContainerSpec{}
+	}
+	return s.Service.Spec.TaskTemplate.ContainerSpec
+}
+
+// UpdateStatus reports the state of the most recent rolling update, if any.
+func (s *SwarmService) UpdateStatus() swarm.UpdateState {
+	if s.Service.UpdateStatus == nil {
+		return ""
+	}
+	return s.Service.UpdateStatus.State
+}
+
+// Scale sets the desired replica count of a replicated service.
+func (s *SwarmService) Scale(replicas uint64) error {
+	if s.Service.Spec.Mode.Replicated == nil {
+		return fmt.Errorf("service %s is not in replicated mode", s.Name())
+	}
+
+	spec := s.Service.Spec
+	spec.Mode.Replicated.Replicas = &replicas
+
+	_, err := s.DockerCommand.client().ServiceUpdate(
+		context.Background(), s.Service.ID, s.Service.Version, spec, types.ServiceUpdateOptions{},
+	)
+	return err
+}
+
+// ForceUpdate restarts every task of the service in place, without changing its spec.
+func (s *SwarmService) ForceUpdate() error {
+	spec := s.Service.Spec
+	spec.TaskTemplate.ForceUpdate++
+
+	_, err := s.DockerCommand.client().ServiceUpdate(
+		context.Background(), s.Service.ID, s.Service.Version, spec, types.ServiceUpdateOptions{},
+	)
+	return err
+}
+
+// Rollback reverts the service to its previous spec.
+func (s *SwarmService) Rollback() error {
+	_, err := s.DockerCommand.client().ServiceUpdate(
+		context.Background(), s.Service.ID, s.Service.Version, s.Service.Spec,
+		types.ServiceUpdateOptions{Rollback: "previous"},
+	)
+	return err
+}
+
+// Remove deletes the service.
+func (s *SwarmService) Remove() error {
+	return s.DockerCommand.client().ServiceRemove(context.Background(), s.Service.ID)
+}
+
+// SwarmTask wraps a swarm.Task, linked back to the lazydocker Container
+// running it (if that container is on this node).
+type SwarmTask struct {
+	Task      swarm.Task
+	Container *Container
+}
+
+// swarmServiceName returns the name of the swarm service with the given ID
+// (e.g. a task's ServiceID), looked up in DockerCommand.SwarmServices, or ""
+// if it isn't known.
+func (c *DockerCommand) swarmServiceName(serviceID string) string {
+	for _, service := range c.SwarmServices {
+		if service.Service.ID == serviceID {
+			return service.Name()
+		}
+	}
+	return ""
+}
+
+// IsSwarmActive reports whether the connected daemon is part of an active
+// swarm, i.e. whether the Swarm panel should be shown at all.
+func (c *DockerCommand) IsSwarmActive() (bool, error) {
+	info, err := c.client().Info(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	return info.Swarm.LocalNodeState == swarm.LocalNodeStateActive, nil
+}
+
+// RefreshSwarmServicesAndTasks populates c.SwarmServices and c.SwarmTasks from
+// ServiceList/TaskList (node details are not fetched), and links each task back to the container
+// running it via the com.docker.swarm.task.id label (the same way
+// assignContainersToServices links compose containers to their Service).
+func (c *DockerCommand) RefreshSwarmServicesAndTasks() ([]*SwarmService, []*SwarmTask, error) {
+	ctx := context.Background()
+
+	// Status: true asks the daemon to include each service's ServiceStatus,
+	// which carries its own RunningTasks/DesiredTasks counts straight from
+	// the orchestrator - correct for both replicated and global mode, and
+	// not something we'd get right ourselves by eyeballing task states
+	// during a rolling update or reschedule.
+	rawServices, err := c.client().ServiceList(ctx, types.ServiceListOptions{Status: true})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rawTasks, err := c.client().TaskList(ctx, types.TaskListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runningTasks := map[string]int{}
+	for _, task := range rawTasks {
+		if task.Status.State == swarm.TaskStateRunning {
+			runningTasks[task.ServiceID]++
+		}
+	}
+
+	services := make([]*SwarmService, len(rawServices))
+	for i, service := range rawServices {
+		running := runningTasks[service.ID]
+		desired := running
+		if service.ServiceStatus != nil {
+			running = int(service.ServiceStatus.RunningTasks)
+			desired = int(service.ServiceStatus.DesiredTasks)
+		}
+
+		services[i] = &SwarmService{
+			Service:       service,
+			StackName:     service.Spec.Labels["com.docker.stack.namespace"],
+			RunningTasks:  running,
+			DesiredTasks:  desired,
+			DockerCommand: c,
+		}
+	}
+
+	// findContainerByTaskID reads c.Containers, so the linking loop has to run
+	// under ServiceMutex too, the same as relinkSwarmTasks requires.
+	c.ServiceMutex.Lock()
+	tasks := make([]*SwarmTask, len(rawTasks))
+	for i, task := range rawTasks {
+		tasks[i] = &SwarmTask{Task: task, Container: c.findContainerByTaskID(task.ID)}
+	}
+
+	c.SwarmServices = services
+	c.SwarmTasks = tasks
+	c.ServiceMutex.Unlock()
+
+	return services, tasks, nil
+}
+
+// findContainerByTaskID looks up the Container running a given swarm task via
+// the com.docker.swarm.task.id label docker stamps onto it.
+func (c *DockerCommand) findContainerByTaskID(taskID string) *Container {
+	for _, container := range c.Containers {
+		if container.Container.Labels["com.docker.swarm.task.id"] == taskID {
+			return container
+		}
+	}
+	return nil
+}
+
+// relinkSwarmTasks re-points every SwarmTask.Container at its container's
+// current *Container value. onContainerUpserted/onContainerGone add and drop
+// entries in c.Containers as container events come in, so without this a
+// task whose container appeared after the last RefreshSwarmServicesAndTasks
+// stays unlinked, and one whose container was destroyed keeps pointing at
+// it; assignContainersToServices handles the same problem for compose
+// services by being re-run on every such event. Callers must hold
+// ServiceMutex.
+func (c *DockerCommand) relinkSwarmTasks() {
+	for _, task := range c.SwarmTasks {
+		task.Container = c.findContainerByTaskID(task.Task.ID)
+	}
+}
+
+// SwarmStacks returns the distinct stack names derived from
+// com.docker.stack.namespace across the known swarm services, so the GUI can
+// filter the Swarm panel down to one stack at a time.
+func (c *DockerCommand) SwarmStacks() []string {
+	seen := map[string]bool{}
+	stacks := []string{}
+	for _, service := range c.SwarmServices {
+		if service.StackName == "" || seen[service.StackName] {
+			continue
+		}
+		seen[service.StackName] = true
+		stacks = append(stacks, service.StackName)
+	}
+	return stacks
+}
@@ -0,0 +1,60 @@
+package config
+
+// AppConfig is the user and app-level configuration lazydocker runs with.
+// DockerCommand only ever reads the UserConfig half of it.
+type AppConfig struct {
+	UserConfig *UserConfig
+}
+
+// UserConfig is the parsed contents of lazydocker's config.yml.
+type UserConfig struct {
+	Gui              GuiConfig
+	CommandTemplates CommandTemplatesConfig
+	Experimental     ExperimentalConfig
+	// Ignore lists substrings of container names that should be hidden from
+	// the containers panel entirely.
+	Ignore []string
+	// DockerContexts lets a user hand-declare extra Docker endpoints
+	// (alongside whatever `docker context` already knows about in
+	// ~/.docker/contexts/meta) that DockerCommand.SwitchContext can move to.
+	DockerContexts []DockerContextConfig
+}
+
+// GuiConfig controls how the containers/services panels render.
+type GuiConfig struct {
+	ShowAllContainers bool
+	// LegacySortContainers falls back to alphabetical sorting instead of
+	// grouping containers by state (running/exited/created) first.
+	LegacySortContainers bool
+}
+
+// CommandTemplatesConfig holds the Go-template command lines lazydocker
+// shells out for in place of calling the docker-compose/docker binaries
+// directly.
+type CommandTemplatesConfig struct {
+	DockerCompose            string
+	CheckDockerComposeConfig string
+	DockerComposeConfig      string
+	ViewAllLogs              string
+}
+
+// ExperimentalConfig gates features that depend on daemon-side experimental
+// APIs and so aren't safe to turn on unconditionally.
+type ExperimentalConfig struct {
+	// Checkpoints enables the checkpoint/restore subsystem, which relies on
+	// the daemon's experimental CRIU-backed checkpoint endpoints.
+	Checkpoints bool
+}
+
+// DockerContextConfig is one hand-declared entry in UserConfig.DockerContexts.
+type DockerContextConfig struct {
+	Name        string
+	Host        string
+	TLSCertPath string
+	// TLSInsecureSkipVerify disables TLS certificate verification for this
+	// context. It defaults to false (verify), unlike a plain TLSVerify bool
+	// would have defaulted to false (skip) when a user's config simply
+	// omits the field.
+	TLSInsecureSkipVerify bool
+	SSHHost               string
+}
@@ -0,0 +1,16 @@
+package i18n
+
+// TranslationSet holds every user-facing string lazydocker prints, so they
+// can all be swapped for a locale at once.
+type TranslationSet struct {
+	CheckpointsNotEnabled   string
+	CheckpointsNotSupported string
+}
+
+// NewTranslationSet returns the (English) default set of translations.
+func NewTranslationSet() *TranslationSet {
+	return &TranslationSet{
+		CheckpointsNotEnabled:   "checkpoints are disabled: enable experimental.checkpoints in your config and restart the daemon with experimental features on",
+		CheckpointsNotSupported: "the daemon rejected the checkpoint request: %v",
+	}
+}